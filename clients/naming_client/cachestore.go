@@ -0,0 +1,146 @@
+package naming_client
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+)
+
+// CacheStore is the local persistence backend behind HostReactor.loadCacheFromDisk
+// and ProcessServiceJson. The default is the existing flat-JSON-file layout;
+// NewBoltCacheStore is an alternative for callers who want atomic, versioned
+// per-service writes instead.
+type CacheStore interface {
+	//Load returns every service currently persisted, keyed by its cache key.
+	Load() (map[string]model.Service, error)
+	//Save persists service under its own cache key. boltCacheStore's write is
+	//atomic; fileCacheStore's is a plain flat-JSON-file write and is not.
+	Save(service model.Service) error
+	//Close releases any resources the store holds open, e.g. boltCacheStore's
+	//underlying file lock. It's safe to call on a store that holds nothing
+	//open; fileCacheStore's is a no-op.
+	Close() error
+}
+
+// fileCacheStore is a CacheStore backed by the existing
+// cache.ReadServicesFromFile / cache.WriteServicesToFile flat-JSON-file layout.
+type fileCacheStore struct {
+	cacheDir string
+}
+
+// NewFileCacheStore returns the default CacheStore, unchanged from the
+// flat-JSON-file behavior HostReactor has always had.
+func NewFileCacheStore(cacheDir string) CacheStore {
+	return &fileCacheStore{cacheDir: cacheDir}
+}
+
+func (s *fileCacheStore) Load() (map[string]model.Service, error) {
+	return cache.ReadServicesFromFile(s.cacheDir), nil
+}
+
+func (s *fileCacheStore) Save(service model.Service) error {
+	cache.WriteServicesToFile(service, s.cacheDir)
+	return nil
+}
+
+// Close is a no-op: fileCacheStore holds nothing open between calls.
+func (s *fileCacheStore) Close() error {
+	return nil
+}
+
+const boltCacheBucket = "nacos_services"
+const boltCacheEntryVersion = 1
+
+// boltCacheEntry is the versioned envelope stored for each service, so a
+// future format change can be detected and migrated on Load.
+type boltCacheEntry struct {
+	Version int           `json:"version"`
+	Service model.Service `json:"service"`
+}
+
+// boltCacheStore is a CacheStore backed by an embedded BoltDB file. Writes go
+// through a single bbolt transaction per service, so a crash mid-write can
+// never leave a truncated, silently-skipped entry the way the flat-JSON-file
+// layout can.
+type boltCacheStore struct {
+	db *bbolt.DB
+}
+
+// NewBoltCacheStore opens (creating if necessary) a BoltDB file at path for
+// use as a HostReactor CacheStore.
+func NewBoltCacheStore(path string) (CacheStore, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(boltCacheBucket))
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltCacheStore{db: db}, nil
+}
+
+func (s *boltCacheStore) Load() (map[string]model.Service, error) {
+	services := make(map[string]model.Service)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltCacheBucket))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var entry boltCacheEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			services[string(k)] = entry.Service
+			return nil
+		})
+	})
+	return services, err
+}
+
+func (s *boltCacheStore) Save(service model.Service) error {
+	key := utils.GetServiceCacheKey(service.Name, service.Clusters)
+	data, err := json.Marshal(boltCacheEntry{Version: boltCacheEntryVersion, Service: service})
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltCacheBucket)).Put([]byte(key), data)
+	})
+}
+
+func (s *boltCacheStore) Close() error {
+	return s.db.Close()
+}
+
+// MigrateFileCacheToStore imports the legacy flat-JSON cache at cacheDir into
+// store, for switching an existing deployment from NewFileCacheStore to
+// NewBoltCacheStore without losing the warm cache. It's a no-op if store
+// already holds entries. NewHostReactor calls this automatically on first run
+// whenever WithCacheStore overrides the default store, so deployments pick up
+// the existing on-disk JSON without an explicit migration step.
+func MigrateFileCacheToStore(store CacheStore, cacheDir string) error {
+	existing, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+	for _, service := range cache.ReadServicesFromFile(cacheDir) {
+		if err := store.Save(service); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package naming_client
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/model"
+)
+
+func TestBoltCacheStoreSaveAndLoadRoundTrip(t *testing.T) {
+	store, err := NewBoltCacheStore(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("NewBoltCacheStore: %v", err)
+	}
+	defer store.Close()
+
+	service := model.Service{Name: "svc", Clusters: "DEFAULT", Hosts: []model.Instance{{InstanceId: "a", Weight: 1}}}
+	if err := store.Save(service); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var found model.Service
+	ok := false
+	for _, svc := range got {
+		if svc.Name == "svc" {
+			found, ok = svc, true
+		}
+	}
+	if !ok {
+		t.Fatalf("Load() = %v, want an entry for the saved service", got)
+	}
+	if len(found.Hosts) != 1 || found.Hosts[0].InstanceId != "a" {
+		t.Fatalf("got hosts %v, want the saved instance round-tripped intact", found.Hosts)
+	}
+}
+
+func TestBoltCacheStoreLoadEmptyIsEmptyNotError(t *testing.T) {
+	store, err := NewBoltCacheStore(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("NewBoltCacheStore: %v", err)
+	}
+	defer store.Close()
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load on a fresh store: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("got %d entries from a fresh store, want 0", len(got))
+	}
+}
+
+type spyCacheStore struct {
+	services map[string]model.Service
+	saved    []model.Service
+}
+
+func (s *spyCacheStore) Load() (map[string]model.Service, error) {
+	return s.services, nil
+}
+
+func (s *spyCacheStore) Save(service model.Service) error {
+	s.saved = append(s.saved, service)
+	return nil
+}
+
+func (s *spyCacheStore) Close() error { return nil }
+
+func TestMigrateFileCacheToStoreNoOpWhenStoreAlreadyHasEntries(t *testing.T) {
+	store := &spyCacheStore{services: map[string]model.Service{"key": {Name: "existing"}}}
+
+	if err := MigrateFileCacheToStore(store, t.TempDir()); err != nil {
+		t.Fatalf("MigrateFileCacheToStore: %v", err)
+	}
+	if len(store.saved) != 0 {
+		t.Fatalf("got %d Save calls against a non-empty store, want 0 - migration should be a no-op", len(store.saved))
+	}
+}
+
+func TestMigrateFileCacheToStoreImportsExistingFileCache(t *testing.T) {
+	dir := t.TempDir()
+	cache.WriteServicesToFile(model.Service{Name: "svc", Clusters: "DEFAULT"}, dir)
+
+	store := &spyCacheStore{services: map[string]model.Service{}}
+	if err := MigrateFileCacheToStore(store, dir); err != nil {
+		t.Fatalf("MigrateFileCacheToStore: %v", err)
+	}
+
+	if len(store.saved) != 1 || store.saved[0].Name != "svc" {
+		t.Fatalf("got saved=%v, want the on-disk service imported into the store", store.saved)
+	}
+}
+
+func TestMigrateFileCacheToStorePropagatesLoadError(t *testing.T) {
+	store := &fakeCacheStore{loadErr: errNoAvailableInstance}
+
+	if err := MigrateFileCacheToStore(store, t.TempDir()); err == nil {
+		t.Fatalf("MigrateFileCacheToStore: got nil error, want store.Load's error propagated")
+	}
+}
@@ -0,0 +1,139 @@
+package naming_client
+
+import (
+	"time"
+)
+
+// HealthListener is notified when a cached service transitions between fresh
+// and stale - Stale fires the moment its refresh first starts failing,
+// Healthy fires once it succeeds again. It does not fire on every failed
+// refresh attempt while an outage continues, so a listener wired to an
+// alert doesn't re-page for the same ongoing outage.
+type HealthListener interface {
+	Stale(serviceName, clusters string, age time.Duration)
+	Healthy(serviceName, clusters string)
+}
+
+// WithHealthListener registers a HealthListener with HostReactor. It's
+// optional; staleness is always logged via Logger regardless of whether one
+// is configured.
+func WithHealthListener(listener HealthListener) HostReactorOption {
+	return func(hr *HostReactor) {
+		hr.healthListener = listener
+	}
+}
+
+// WithMaxStaleness bounds how long since its last successful fetch a cached
+// service is allowed to get before GetServiceInfo refuses to serve it and
+// returns an error instead - for callers that would rather fail fast than act
+// on very old membership data. The bound applies whether or not a refresh
+// failure has been recorded (see exceedsMaxStaleness). Zero (the default) means no bound:
+// GetServiceInfo always serves whatever is cached.
+func WithMaxStaleness(d time.Duration) HostReactorOption {
+	return func(hr *HostReactor) {
+		hr.maxStaleness = d
+	}
+}
+
+// recordFailure marks key as having just failed a refresh attempt, logs it,
+// and - the first time this happens since its last success - notifies the
+// HealthListener that it went stale.
+func (hr *HostReactor) recordFailure(key, serviceName, clusters string) {
+	count := 1
+	if v, ok := hr.failureCountMap.Get(key); ok {
+		count = v.(int) + 1
+	}
+	hr.failureCountMap.Set(key, count)
+
+	age, _ := hr.staleAge(key)
+	hr.logger.Warn("serving stale cached service, nacos unreachable", "serviceName", serviceName, "clusters", clusters, "age", age.String(), "consecutiveFailures", count)
+	if count == 1 && hr.healthListener != nil {
+		hr.healthListener.Stale(serviceName, clusters, age)
+	}
+}
+
+// recordSuccess clears key's failure count and, if it was stale, notifies the
+// HealthListener that it recovered.
+func (hr *HostReactor) recordSuccess(key, serviceName, clusters string) {
+	v, hadFailures := hr.failureCountMap.Get(key)
+	hr.failureCountMap.Set(key, 0)
+	if hadFailures && v.(int) > 0 && hr.healthListener != nil {
+		hr.healthListener.Healthy(serviceName, clusters)
+	}
+}
+
+// isStale reports whether key's most recent refresh attempt failed.
+func (hr *HostReactor) isStale(key string) bool {
+	v, ok := hr.failureCountMap.Get(key)
+	return ok && v.(int) > 0
+}
+
+// lastUpdated returns when key was last confirmed fresh by a successful
+// query, or false if it never has been in this process.
+func (hr *HostReactor) lastUpdated(key string) (time.Time, bool) {
+	v, ok := hr.updateTimeMap.Get(key)
+	if !ok {
+		return time.Time{}, false
+	}
+	millis := int64(v.(uint64))
+	return time.Unix(0, millis*int64(time.Millisecond)), true
+}
+
+// staleAge returns how long it's been since key was last confirmed fresh,
+// and whether that's known at all - it isn't for an entry adopted straight
+// from the on-disk CacheStore that's never had a successful in-process fetch.
+func (hr *HostReactor) staleAge(key string) (time.Duration, bool) {
+	last, ok := hr.lastUpdated(key)
+	if !ok {
+		return 0, false
+	}
+	return time.Since(last), true
+}
+
+// exceedsMaxStaleness reports whether key should be refused by GetServiceInfo
+// under the configured WithMaxStaleness bound. This is judged from
+// LastUpdated alone, not from isStale: a key loaded straight from the
+// on-disk CacheStore at startup (or adopted from it on failover) and never
+// since confirmed fresh by a live fetch has no failure recorded against it
+// either, so gating on isStale would let an arbitrarily old disk snapshot be
+// served forever. An unknown age (no successful in-process fetch on record)
+// always exceeds a configured bound, since there's no way to tell how old
+// the data actually is.
+func (hr *HostReactor) exceedsMaxStaleness(key string) bool {
+	if hr.maxStaleness <= 0 {
+		return false
+	}
+	age, known := hr.staleAge(key)
+	return !known || age > hr.maxStaleness
+}
+
+// adoptDiskCache is the last resort when a service has never been fetched in
+// this process and the live QueryList call just failed: rather than surface
+// an error when the on-disk CacheStore already has a (possibly old) copy,
+// adopt it into serviceInfoMap. The caller is responsible for having already
+// called recordFailure - this only supplies data to go with that failure.
+// Returns false if the CacheStore has nothing for key either, in which case
+// the caller's error stands.
+func (hr *HostReactor) adoptDiskCache(key, serviceName, clusters string) bool {
+	services, err := hr.cacheStore.Load()
+	if err != nil {
+		hr.logger.Error("failover load from cache store failed", "serviceName", serviceName, "clusters", clusters, "err", err.Error())
+		return false
+	}
+	service, ok := services[key]
+	if !ok {
+		return false
+	}
+	hr.serviceInfoMap.Set(key, newCachedService(service))
+	return true
+}
+
+// recordFailureAndFailover records key's failed refresh and, if nothing is
+// cached for it yet, tries to adopt a copy from the on-disk CacheStore so
+// there's still something to serve.
+func (hr *HostReactor) recordFailureAndFailover(key, serviceName, clusters string) {
+	hr.recordFailure(key, serviceName, clusters)
+	if _, ok := hr.serviceInfoMap.Get(key); !ok {
+		hr.adoptDiskCache(key, serviceName, clusters)
+	}
+}
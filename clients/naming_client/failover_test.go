@@ -0,0 +1,167 @@
+package naming_client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/model"
+)
+
+func newTestHostReactor() *HostReactor {
+	return &HostReactor{
+		serviceInfoMap:  cache.NewConcurrentMap(),
+		updateTimeMap:   cache.NewConcurrentMap(),
+		failureCountMap: cache.NewConcurrentMap(),
+		logger:          stdLogger{},
+	}
+}
+
+type fakeHealthListener struct {
+	staleCalls   int
+	healthyCalls int
+}
+
+func (f *fakeHealthListener) Stale(serviceName, clusters string, age time.Duration) { f.staleCalls++ }
+func (f *fakeHealthListener) Healthy(serviceName, clusters string)                  { f.healthyCalls++ }
+
+type fakeCacheStore struct {
+	services map[string]model.Service
+	loadErr  error
+}
+
+func (f *fakeCacheStore) Load() (map[string]model.Service, error) {
+	if f.loadErr != nil {
+		return nil, f.loadErr
+	}
+	return f.services, nil
+}
+
+func (f *fakeCacheStore) Save(service model.Service) error { return nil }
+
+func (f *fakeCacheStore) Close() error { return nil }
+
+func TestRecordFailureFiresStaleOnlyOnFirstFailure(t *testing.T) {
+	hr := newTestHostReactor()
+	listener := &fakeHealthListener{}
+	hr.healthListener = listener
+
+	hr.recordFailure("key", "svc", "")
+	hr.recordFailure("key", "svc", "")
+	hr.recordFailure("key", "svc", "")
+
+	if listener.staleCalls != 1 {
+		t.Fatalf("got %d Stale calls across 3 consecutive failures, want 1", listener.staleCalls)
+	}
+	if !hr.isStale("key") {
+		t.Fatalf("isStale(key) = false after failures, want true")
+	}
+}
+
+func TestRecordSuccessFiresHealthyOnlyAfterFailures(t *testing.T) {
+	hr := newTestHostReactor()
+	listener := &fakeHealthListener{}
+	hr.healthListener = listener
+
+	hr.recordSuccess("key", "svc", "")
+	if listener.healthyCalls != 0 {
+		t.Fatalf("got %d Healthy calls with no prior failure, want 0", listener.healthyCalls)
+	}
+
+	hr.recordFailure("key", "svc", "")
+	hr.recordSuccess("key", "svc", "")
+	if listener.healthyCalls != 1 {
+		t.Fatalf("got %d Healthy calls after a recovered failure, want 1", listener.healthyCalls)
+	}
+	if hr.isStale("key") {
+		t.Fatalf("isStale(key) = true after recordSuccess, want false")
+	}
+}
+
+func TestExceedsMaxStalenessJudgesAgeEvenWithoutARecordedFailure(t *testing.T) {
+	hr := newTestHostReactor()
+	hr.maxStaleness = time.Hour
+
+	if !hr.exceedsMaxStaleness("key") {
+		t.Fatalf("exceedsMaxStaleness = false for a key with no known age (e.g. adopted from the on-disk cache), want true - there's no way to tell how old it is")
+	}
+
+	hr.updateTimeMap.Set("key", uint64(time.Now().Add(-10*time.Minute).UnixNano()/int64(time.Millisecond)))
+	if hr.exceedsMaxStaleness("key") {
+		t.Fatalf("exceedsMaxStaleness = true for a key fetched 10m ago with a 1h bound and no failures, want false")
+	}
+
+	hr.updateTimeMap.Set("key", uint64(time.Now().Add(-2*time.Hour).UnixNano()/int64(time.Millisecond)))
+	if !hr.exceedsMaxStaleness("key") {
+		t.Fatalf("exceedsMaxStaleness = false for a key last fetched 2h ago with a 1h bound, want true - age alone is enough, regardless of isStale")
+	}
+}
+
+func TestExceedsMaxStalenessUnboundedWhenZero(t *testing.T) {
+	hr := newTestHostReactor()
+	hr.recordFailure("key", "svc", "")
+	hr.updateTimeMap.Set("key", uint64(time.Now().Add(-24*time.Hour).UnixNano()/int64(time.Millisecond)))
+
+	if hr.exceedsMaxStaleness("key") {
+		t.Fatalf("exceedsMaxStaleness = true with maxStaleness unset, want false (no bound configured)")
+	}
+}
+
+func TestAdoptDiskCacheImportsMatchingEntry(t *testing.T) {
+	hr := newTestHostReactor()
+	hr.cacheStore = &fakeCacheStore{services: map[string]model.Service{
+		"key": {Name: "svc", Hosts: []model.Instance{{InstanceId: "a", Healthy: true, Enable: true}}},
+	}}
+
+	if ok := hr.adoptDiskCache("key", "svc", ""); !ok {
+		t.Fatalf("adoptDiskCache = false, want true when the CacheStore has a matching entry")
+	}
+	cached, ok := hr.serviceInfoMap.Get("key")
+	if !ok {
+		t.Fatalf("serviceInfoMap has no entry for key after a successful adoptDiskCache")
+	}
+	if cached.(cachedService).service.Name != "svc" {
+		t.Fatalf("got service name %q, want %q", cached.(cachedService).service.Name, "svc")
+	}
+}
+
+func TestAdoptDiskCacheReturnsFalseWhenKeyMissing(t *testing.T) {
+	hr := newTestHostReactor()
+	hr.cacheStore = &fakeCacheStore{services: map[string]model.Service{}}
+
+	if ok := hr.adoptDiskCache("key", "svc", ""); ok {
+		t.Fatalf("adoptDiskCache = true, want false when the CacheStore has nothing for key")
+	}
+	if _, ok := hr.serviceInfoMap.Get("key"); ok {
+		t.Fatalf("serviceInfoMap gained an entry despite adoptDiskCache failing")
+	}
+}
+
+func TestRecordFailureAndFailoverLeavesExistingCacheAlone(t *testing.T) {
+	hr := newTestHostReactor()
+	hr.serviceInfoMap.Set("key", newCachedService(model.Service{Name: "already-cached"}))
+	hr.cacheStore = &fakeCacheStore{services: map[string]model.Service{
+		"key": {Name: "from-disk"},
+	}}
+
+	hr.recordFailureAndFailover("key", "svc", "")
+
+	cached, _ := hr.serviceInfoMap.Get("key")
+	if cached.(cachedService).service.Name != "already-cached" {
+		t.Fatalf("recordFailureAndFailover overwrote an already-cached entry with the disk copy")
+	}
+}
+
+func TestRecordFailureAndFailoverAdoptsDiskCacheWhenNothingCached(t *testing.T) {
+	hr := newTestHostReactor()
+	hr.cacheStore = &fakeCacheStore{services: map[string]model.Service{
+		"key": {Name: "from-disk"},
+	}}
+
+	hr.recordFailureAndFailover("key", "svc", "")
+
+	cached, ok := hr.serviceInfoMap.Get("key")
+	if !ok || cached.(cachedService).service.Name != "from-disk" {
+		t.Fatalf("recordFailureAndFailover did not adopt the disk cache for an uncached key")
+	}
+}
@@ -1,11 +1,11 @@
 package naming_client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"log"
+	"fmt"
 	"reflect"
-	"sync"
 	"time"
 
 	"github.com/nacos-group/nacos-sdk-go/clients/cache"
@@ -22,12 +22,77 @@ type HostReactor struct {
 	subCallback          SubscribeCallback
 	updateTimeMap        cache.ConcurrentMap
 	updateCacheWhenEmpty bool
-	lock                 *sync.Mutex
+	updateGroup          *singleflightGroup
+	subscribedMap        cache.ConcurrentMap
+	scheduler            *refreshScheduler
+	cacheStore           CacheStore
+	logger               Logger
+	failureCountMap      cache.ConcurrentMap
+	healthListener       HealthListener
+	maxStaleness         time.Duration
+}
+
+// HostReactorOption configures optional HostReactor behavior in NewHostReactor.
+type HostReactorOption func(*HostReactor)
+
+// WithCacheStore overrides the local cache backend, e.g. to NewBoltCacheStore,
+// instead of the default flat-JSON-file layout. NewHostReactor migrates any
+// existing flat-JSON cache at cacheDir into the new store on first run.
+func WithCacheStore(store CacheStore) HostReactorOption {
+	return func(hr *HostReactor) {
+		hr.cacheStore = store
+	}
+}
+
+// subscription records what a Subscribe call asked to keep fresh, so the
+// refresh loop can turn a due cache key back into a serviceProxy.QueryList call.
+type subscription struct {
+	serviceName string
+	clusters    string
+}
+
+// allSelectorTypes lists every SelectorType newCachedService must precompute
+// a Selector for.
+var allSelectorTypes = []SelectorType{SelectorWeightedRandom, SelectorRoundRobin, SelectorConsistentHash}
+
+// cachedService pairs a fetched model.Service with the selector state built
+// from its host list, so load-balancing state rides alongside the service in
+// serviceInfoMap instead of being rebuilt on every SelectInstance call.
+// selectors is built once in newCachedService and never written to again -
+// cachedService is stored by value in serviceInfoMap, so concurrent readers
+// share one selectors map, and a map that's only ever read concurrently is
+// safe without a lock.
+type cachedService struct {
+	service   model.Service
+	selectors map[SelectorType]Selector
+}
+
+func selectableInstances(hosts []model.Instance) []model.Instance {
+	instances := make([]model.Instance, 0, len(hosts))
+	for _, host := range hosts {
+		if host.Healthy && host.Enable {
+			instances = append(instances, host)
+		}
+	}
+	return instances
+}
+
+func newCachedService(service model.Service) cachedService {
+	instances := selectableInstances(service.Hosts)
+	selectors := make(map[SelectorType]Selector, len(allSelectorTypes))
+	for _, selectorType := range allSelectorTypes {
+		selectors[selectorType] = newSelector(selectorType, instances)
+	}
+	return cachedService{service: service, selectors: selectors}
+}
+
+func (cs *cachedService) selector(selectorType SelectorType) Selector {
+	return cs.selectors[selectorType]
 }
 
 const Default_Update_Thread_Num = 20
 
-func NewHostReactor(serviceProxy NamingProxy, cacheDir string, updateThreadNum int, notLoadCacheAtStart bool, subCallback SubscribeCallback, updateCacheWhenEmpty bool) HostReactor {
+func NewHostReactor(serviceProxy NamingProxy, cacheDir string, updateThreadNum int, notLoadCacheAtStart bool, subCallback SubscribeCallback, updateCacheWhenEmpty bool, opts ...HostReactorOption) HostReactor {
 	if updateThreadNum <= 0 {
 		updateThreadNum = Default_Update_Thread_Num
 	}
@@ -39,7 +104,21 @@ func NewHostReactor(serviceProxy NamingProxy, cacheDir string, updateThreadNum i
 		subCallback:          subCallback,
 		updateTimeMap:        cache.NewConcurrentMap(),
 		updateCacheWhenEmpty: updateCacheWhenEmpty,
-		lock:                 new(sync.Mutex),
+		updateGroup:          newSingleflightGroup(),
+		subscribedMap:        cache.NewConcurrentMap(),
+		scheduler:            newRefreshScheduler(),
+		failureCountMap:      cache.NewConcurrentMap(),
+	}
+	for _, opt := range opts {
+		opt(&hr)
+	}
+	if hr.logger == nil {
+		hr.logger = newStdLogger()
+	}
+	if hr.cacheStore == nil {
+		hr.cacheStore = NewFileCacheStore(cacheDir)
+	} else if err := MigrateFileCacheToStore(hr.cacheStore, cacheDir); err != nil {
+		hr.logger.Error("migrate file cache to store failed", "err", err.Error())
 	}
 	pr := NewPushRecevier(&hr)
 	hr.pushReceiver = *pr
@@ -50,13 +129,33 @@ func NewHostReactor(serviceProxy NamingProxy, cacheDir string, updateThreadNum i
 	return hr
 }
 
+// Subscribe registers serviceName/clusters to be kept fresh by the background
+// update loop, waking it so the first refresh happens immediately rather than
+// waiting for the next tick. Services nobody has subscribed to are never
+// refreshed in the background; GetServiceInfo still fetches them synchronously
+// on a cache miss, it just won't keep them warm afterwards.
+func (hr *HostReactor) Subscribe(serviceName, clusters string) {
+	key := utils.GetServiceCacheKey(serviceName, clusters)
+	hr.subscribedMap.Set(key, subscription{serviceName: serviceName, clusters: clusters})
+	hr.scheduler.schedule(key, uint64(utils.CurrentMillis()))
+}
+
+// Unsubscribe stops serviceName/clusters from being refreshed in the
+// background. Whatever is currently cached for it is left in place.
+func (hr *HostReactor) Unsubscribe(serviceName, clusters string) {
+	key := utils.GetServiceCacheKey(serviceName, clusters)
+	hr.subscribedMap.Remove(key)
+	hr.scheduler.cancel(key)
+}
+
 func (hr *HostReactor) loadCacheFromDisk() {
-	serviceMap := cache.ReadServicesFromFile(hr.cacheDir)
-	if serviceMap == nil || len(serviceMap) == 0 {
+	serviceMap, err := hr.cacheStore.Load()
+	if err != nil {
+		hr.logger.Error("load service cache failed", "err", err.Error())
 		return
 	}
 	for k, v := range serviceMap {
-		hr.serviceInfoMap.Set(k, v)
+		hr.serviceInfoMap.Set(k, newCachedService(v))
 	}
 }
 
@@ -67,109 +166,349 @@ func (hr *HostReactor) ProcessServiceJson(result string) {
 	}
 	cacheKey := utils.GetServiceCacheKey(service.Name, service.Clusters)
 
-	oldDomain, ok := hr.serviceInfoMap.Get(cacheKey)
+	oldCached, ok := hr.serviceInfoMap.Get(cacheKey)
 	if ok && !hr.updateCacheWhenEmpty {
 		//if instance list is empty,not to update cache
 		if service.Hosts == nil || len(service.Hosts) == 0 {
-			log.Printf("[ERROR]:do not have useful host, ignore it, name:%s \n", service.Name)
+			hr.logger.Error("do not have useful host, ignore it", "name", service.Name)
 			return
 		}
 	}
 	hr.updateTimeMap.Set(cacheKey, uint64(utils.CurrentMillis()))
-	hr.serviceInfoMap.Set(cacheKey, *service)
-	if !ok || ok && !reflect.DeepEqual(service.Hosts, oldDomain.(model.Service).Hosts) {
+	hr.recordSuccess(cacheKey, service.Name, service.Clusters)
+	if _, subscribed := hr.subscribedMap.Get(cacheKey); subscribed {
+		//this update - whether it came from our own poll or a server-pushed UDP
+		//notification - makes the service fresh again, so push its next due
+		//refresh out instead of letting the scheduler re-fetch it right away.
+		//CacheMillis is clamped to minRefreshIntervalMillis so a service whose
+		//JSON omits or zeroes it can't turn into a hot loop hammering QueryList.
+		hr.scheduler.schedule(cacheKey, uint64(utils.CurrentMillis())+refreshIntervalMillis(service.CacheMillis))
+	}
+	hostsChanged := !ok || !reflect.DeepEqual(service.Hosts, oldCached.(cachedService).service.Hosts)
+	if hostsChanged {
+		//hosts actually changed: selector state is stale, rebuild it now
+		hr.serviceInfoMap.Set(cacheKey, newCachedService(*service))
+	} else {
+		//hosts unchanged: keep the existing selector state, just refresh the service metadata
+		cached := oldCached.(cachedService)
+		cached.service = *service
+		hr.serviceInfoMap.Set(cacheKey, cached)
+	}
+	if hostsChanged {
 		if !ok {
-			log.Println("[INFO] service not found in cache " + cacheKey)
+			hr.logger.Info("service not found in cache", "key", cacheKey)
 		} else {
-			log.Printf("[INFO] service key:%s was updated to:%s \n", cacheKey, utils.ToJsonString(service))
+			//full service JSON is Debug, not Info, so production logs don't
+			//balloon on services that churn often
+			hr.logger.Debug("service was updated", "key", cacheKey, "service", utils.ToJsonString(service))
+		}
+		if err := hr.cacheStore.Save(*service); err != nil {
+			hr.logger.Error("save service cache failed", "name", service.Name, "err", err.Error())
 		}
-		cache.WriteServicesToFile(*service, hr.cacheDir)
 		hr.subCallback.ServiceChanged(service)
 	}
 }
 
-func (hr *HostReactor) GetServiceInfo(serviceName string, clusters string) (model.Service, error) {
+// ServiceInfo is what GetServiceInfo returns: the cached model.Service plus
+// the staleness metadata callers need to decide whether to trust it - when it
+// was last confirmed fresh, and whether the most recent refresh attempt
+// failed.
+type ServiceInfo struct {
+	model.Service
+	// LastUpdated is when this service was last confirmed fresh by a
+	// successful query to Nacos. It's the zero time if that has never
+	// happened in this process, e.g. the data was adopted straight from the
+	// on-disk CacheStore after Nacos was unreachable on the first fetch.
+	LastUpdated time.Time
+	// Stale is true when the most recent refresh attempt for this service
+	// failed, so LastUpdated may be out of date.
+	Stale bool
+}
+
+func (hr *HostReactor) toServiceInfo(key string, service model.Service) ServiceInfo {
+	lastUpdated, _ := hr.lastUpdated(key)
+	return ServiceInfo{Service: service, LastUpdated: lastUpdated, Stale: hr.isStale(key)}
+}
+
+// GetServiceInfo returns serviceName/clusters, refusing to serve cached data
+// whose last successful fetch is older than WithMaxStaleness - see
+// ServiceInfo for how callers can make that same judgment themselves instead.
+func (hr *HostReactor) GetServiceInfo(ctx context.Context, serviceName string, clusters string) (ServiceInfo, error) {
 	key := utils.GetServiceCacheKey(serviceName, clusters)
-	cacheService, ok := hr.serviceInfoMap.Get(key)
+	cached, ok := hr.serviceInfoMap.Get(key)
 	if !ok {
-		hr.updateServiceNow(serviceName, clusters, key)
-		if cacheService, ok = hr.serviceInfoMap.Get(key); !ok {
-			return model.Service{}, errors.New("get service info failed")
+		if err := hr.updateServiceNow(ctx, serviceName, clusters, key); err != nil {
+			return ServiceInfo{}, err
+		}
+		if cached, ok = hr.serviceInfoMap.Get(key); !ok {
+			return ServiceInfo{}, errors.New("get service info failed")
 		}
 	}
+	if hr.exceedsMaxStaleness(key) {
+		age, _ := hr.staleAge(key)
+		return ServiceInfo{}, fmt.Errorf("cached service %s exceeds max staleness %s (age %s)", key, hr.maxStaleness, age)
+	}
 
-	return cacheService.(model.Service), nil
+	return hr.toServiceInfo(key, cached.(cachedService).service), nil
 }
 
-func (hr *HostReactor) GetAllServiceInfo(nameSpace, groupName string, pageNo, pageSize uint32) model.ServiceList {
+// SelectInstance returns a single instance of serviceName/clusters chosen by
+// selectorType. selectKey is the routing key used by SelectorConsistentHash
+// (e.g. a user or session ID) and is ignored by the other strategies.
+func (hr *HostReactor) SelectInstance(ctx context.Context, serviceName, clusters string, selectorType SelectorType, selectKey string) (model.Instance, error) {
+	key := utils.GetServiceCacheKey(serviceName, clusters)
+	cached, ok := hr.serviceInfoMap.Get(key)
+	if !ok {
+		if err := hr.updateServiceNow(ctx, serviceName, clusters, key); err != nil {
+			return model.Instance{}, err
+		}
+		if cached, ok = hr.serviceInfoMap.Get(key); !ok {
+			return model.Instance{}, errors.New("get service info failed")
+		}
+	}
+	cs := cached.(cachedService)
+	selector := cs.selector(selectorType)
+	if selector == nil {
+		return model.Instance{}, errUnknownSelectorType
+	}
+	return selector.Select(selectKey)
+}
+
+// SelectInstances returns up to count distinct instances of serviceName/clusters
+// chosen by selectorType, in selection order. It returns exactly count
+// instances whenever the service has at least count healthy hosts, and fewer
+// only when it has fewer healthy hosts than requested.
+//
+// selectorType's Selector is consulted through MultiSelector when it
+// implements it (both SelectorWeightedRandom and SelectorConsistentHash do):
+// Select alone can't guarantee distinctness for either - it's deterministic
+// per key for consistent hash, and weighted random's repeated-draw-until-new
+// approach can exhaust its attempt budget on weight collisions without ever
+// covering every instance.
+func (hr *HostReactor) SelectInstances(ctx context.Context, serviceName, clusters string, selectorType SelectorType, selectKey string, count int) ([]model.Instance, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	key := utils.GetServiceCacheKey(serviceName, clusters)
+	cached, ok := hr.serviceInfoMap.Get(key)
+	if !ok {
+		if err := hr.updateServiceNow(ctx, serviceName, clusters, key); err != nil {
+			return nil, err
+		}
+		if cached, ok = hr.serviceInfoMap.Get(key); !ok {
+			return nil, errors.New("get service info failed")
+		}
+	}
+	cs := cached.(cachedService)
+	healthy := selectableInstances(cs.service.Hosts)
+	if len(healthy) == 0 {
+		return nil, errNoAvailableInstance
+	}
+	if count > len(healthy) {
+		count = len(healthy)
+	}
+	selector := cs.selector(selectorType)
+	if selector == nil {
+		return nil, errUnknownSelectorType
+	}
+	if multi, ok := selector.(MultiSelector); ok {
+		return multi.SelectN(selectKey, count)
+	}
+	instances := make([]model.Instance, 0, count)
+	seen := make(map[string]bool, count)
+	maxAttempts := count * 10
+	for attempt := 0; len(instances) < count && attempt < maxAttempts; attempt++ {
+		instance, err := selector.Select(selectKey)
+		if err != nil {
+			return nil, err
+		}
+		if seen[instance.InstanceId] {
+			continue
+		}
+		seen[instance.InstanceId] = true
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+// GetAllServiceInfo fetches nameSpace/groupName's paged service list. Like
+// updateServiceNow, ctx only bounds how long this caller waits - the
+// GetAllServiceInfoList call backing it keeps running in the background after
+// ctx is cancelled, since cancelling the underlying request would require a
+// ctx-aware NamingProxy.GetAllServiceInfoList, which this change doesn't add.
+func (hr *HostReactor) GetAllServiceInfo(ctx context.Context, nameSpace, groupName string, pageNo, pageSize uint32) model.ServiceList {
 	data := model.ServiceList{}
-	result, err := hr.serviceProxy.GetAllServiceInfoList(nameSpace, groupName, pageNo, pageSize)
-	if err != nil {
-		log.Printf("[ERROR]:query all services info return error!nameSpace:%s groupName:%s pageNo:%d, pageSize:%d err:%s \n", nameSpace, groupName, pageNo, pageSize, err.Error())
+	type queryResult struct {
+		result string
+		err    error
+	}
+	resultCh := make(chan queryResult, 1)
+	go func() {
+		result, err := hr.serviceProxy.GetAllServiceInfoList(nameSpace, groupName, pageNo, pageSize)
+		resultCh <- queryResult{result, err}
+	}()
+
+	var result string
+	select {
+	case <-ctx.Done():
+		hr.logger.Error("query all services info cancelled", "nameSpace", nameSpace, "groupName", groupName, "pageNo", pageNo, "pageSize", pageSize, "err", ctx.Err().Error())
 		return data
+	case r := <-resultCh:
+		if r.err != nil {
+			hr.logger.Error("query all services info return error", "nameSpace", nameSpace, "groupName", groupName, "pageNo", pageNo, "pageSize", pageSize, "err", r.err.Error())
+			return data
+		}
+		result = r.result
 	}
 	if result == "" {
-		log.Printf("[ERROR]:query all services info is empty!nameSpace:%s  groupName:%s pageNo:%d, pageSize:%d \n", nameSpace, groupName, pageNo, pageSize)
+		hr.logger.Error("query all services info is empty", "nameSpace", nameSpace, "groupName", groupName, "pageNo", pageNo, "pageSize", pageSize)
 		return data
 	}
 
-	err = json.Unmarshal([]byte(result), &data)
+	err := json.Unmarshal([]byte(result), &data)
 	if err != nil {
-		log.Printf("[ERROR]: the result of quering all services info json.Unmarshal error !nameSpace:%s groupName:%s pageNo:%d, pageSize:%d \n", nameSpace, groupName, pageNo, pageSize)
+		hr.logger.Error("json.Unmarshal of all services info failed", "nameSpace", nameSpace, "groupName", groupName, "pageNo", pageNo, "pageSize", pageSize)
 		return data
 	}
 	return data
 }
 
-func (hr *HostReactor) updateServiceNow(serviceName, clusters, key string) {
-	hr.lock.Lock()
-	if _, ok := hr.serviceInfoMap.Get(key); !ok {
+// updateServiceNow performs a synchronous, first-time fetch of serviceName/clusters
+// from the Nacos server. Concurrent callers for the same key share one in-flight
+// request via updateGroup, while callers for different keys run fully in
+// parallel. ctx only bounds how long THIS CALLER is willing to wait - cancelling
+// it makes updateServiceNow return early, but does not abort the underlying
+// QueryList call, which keeps running in the background and still populates
+// the cache for the next caller. True cancellation would require threading
+// ctx into NamingProxy.QueryList itself, which is outside this change: that
+// interface isn't touched here, so a cancelled caller can still leave an
+// in-flight HTTP request running against Nacos after it stops waiting on it.
+func (hr *HostReactor) updateServiceNow(ctx context.Context, serviceName, clusters, key string) error {
+	if _, ok := hr.serviceInfoMap.Get(key); ok {
+		return nil
+	}
+	resultCh := hr.updateGroup.DoChan(key, func() (interface{}, error) {
 		result, err := hr.serviceProxy.QueryList(serviceName, clusters, hr.pushReceiver.port, false)
-
 		if err != nil {
-			log.Printf("[ERROR]:query list return error!servieName:%s cluster:%s  err:%s \n", serviceName, clusters, err.Error())
-			return
+			hr.logger.Error("query list return error", "serviceName", serviceName, "cluster", clusters, "err", err.Error())
+			hr.recordFailureAndFailover(key, serviceName, clusters)
+			if _, ok := hr.serviceInfoMap.Get(key); ok {
+				return nil, nil
+			}
+			return nil, err
 		}
 		if result == "" {
-			log.Printf("[ERROR]:query list is empty!servieName:%s cluster:%s \n", serviceName, clusters)
-			return
+			hr.logger.Error("query list is empty", "serviceName", serviceName, "cluster", clusters)
+			hr.recordFailureAndFailover(key, serviceName, clusters)
+			if _, ok := hr.serviceInfoMap.Get(key); ok {
+				return nil, nil
+			}
+			return nil, errors.New("query list is empty")
 		}
 		hr.ProcessServiceJson(result)
+		return nil, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case r := <-resultCh:
+		return r.err
 	}
-	hr.lock.Unlock()
 }
 
+// asyncUpdateService is the background refresh loop. Instead of polling every
+// subscribed service once a second, it sleeps until the scheduler's next due
+// entry and wakes early whenever Subscribe/Unsubscribe or a fresh
+// ProcessServiceJson update changes that due time.
 func (hr *HostReactor) asyncUpdateService() {
 	sema := utils.NewSemaphore(hr.updateThreadNum)
 	for {
-		for _, v := range hr.serviceInfoMap.Items() {
-			service := v.(model.Service)
-			lastRefTime, ok := hr.updateTimeMap.Get(utils.GetServiceCacheKey(service.Name, service.Clusters))
-			if !ok {
-				lastRefTime = uint64(0)
+		var wait time.Duration
+		if _, dueAt, ok := hr.scheduler.next(); ok {
+			if remaining := int64(dueAt) - utils.CurrentMillis(); remaining > 0 {
+				wait = time.Duration(remaining) * time.Millisecond
 			}
-			if uint64(utils.CurrentMillis())-lastRefTime.(uint64) > service.CacheMillis {
-				sema.Acquire()
-				go func() {
-					hr.asyncUpdateServiceNow(service.Name, service.Clusters)
-					sema.Release()
-				}()
+		} else {
+			//nothing subscribed: sleep until Subscribe wakes us, no need to poll
+			wait = time.Hour
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-hr.scheduler.wake:
+			timer.Stop()
+			continue
+		}
+
+		for {
+			key, dueAt, ok := hr.scheduler.next()
+			if !ok || int64(dueAt) > utils.CurrentMillis() {
+				break
+			}
+			subValue, subscribed := hr.subscribedMap.Get(key)
+			if !subscribed {
+				hr.scheduler.cancel(key)
+				continue
 			}
+			//remove the entry now so this inner loop doesn't keep re-popping it
+			//while the refresh is in flight; ProcessServiceJson reschedules it
+			//once the refresh lands.
+			hr.scheduler.cancel(key)
+			sub := subValue.(subscription)
+			sema.Acquire()
+			go func(key string, sub subscription) {
+				defer sema.Release()
+				hr.asyncUpdateServiceNow(key, sub.serviceName, sub.clusters)
+			}(key, sub)
 		}
-		time.Sleep(1 * time.Second)
 	}
 }
 
-func (hr *HostReactor) asyncUpdateServiceNow(serviceName, clusters string) {
+// failedRefreshRetryMillis bounds how long a subscribed service waits for its
+// next refresh attempt after a failed query, independent of its CacheMillis.
+const failedRefreshRetryMillis = uint64(3000)
+
+// minRefreshIntervalMillis floors how often a subscribed service can be
+// refreshed, regardless of its server-reported CacheMillis - the same floor
+// the old fixed-interval poll loop gave every service implicitly by sleeping
+// a second between passes. Without it, a service whose JSON omits or zeroes
+// CacheMillis would become due again the instant its refresh succeeds,
+// turning the background loop into a hot loop against Nacos.
+const minRefreshIntervalMillis = uint64(1000)
+
+// refreshIntervalMillis clamps cacheMillis to minRefreshIntervalMillis.
+func refreshIntervalMillis(cacheMillis uint64) uint64 {
+	if cacheMillis < minRefreshIntervalMillis {
+		return minRefreshIntervalMillis
+	}
+	return cacheMillis
+}
+
+func (hr *HostReactor) asyncUpdateServiceNow(key, serviceName, clusters string) {
 	result, err := hr.serviceProxy.QueryList(serviceName, clusters, hr.pushReceiver.port, false)
 
 	if err != nil {
-		log.Printf("[ERROR]:query list return error!servieName:%s cluster:%s  err:%s \n", serviceName, clusters, err.Error())
+		hr.logger.Error("query list return error", "serviceName", serviceName, "cluster", clusters, "err", err.Error())
+		hr.recordFailureAndFailover(key, serviceName, clusters)
+		hr.retrySubscription(key)
 		return
 	}
 	if result == "" {
-		log.Printf("[ERROR]:query list is empty!servieName:%s cluster:%s \n", serviceName, clusters)
+		hr.logger.Error("query list is empty", "serviceName", serviceName, "cluster", clusters)
+		hr.recordFailureAndFailover(key, serviceName, clusters)
+		hr.retrySubscription(key)
 		return
 	}
 	hr.ProcessServiceJson(result)
 }
+
+// retrySubscription reschedules key for another attempt after a failed
+// refresh, provided it's still subscribed - ProcessServiceJson only
+// reschedules on success, so a failing service would otherwise drop out of
+// the refresh loop entirely until something else touched it.
+func (hr *HostReactor) retrySubscription(key string) {
+	if _, subscribed := hr.subscribedMap.Get(key); subscribed {
+		hr.scheduler.schedule(key, uint64(utils.CurrentMillis())+failedRefreshRetryMillis)
+	}
+}
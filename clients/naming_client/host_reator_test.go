@@ -0,0 +1,175 @@
+package naming_client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/utils"
+)
+
+// fakeNamingProxy lets these tests control exactly what a live fetch returns
+// without talking to a real Nacos server. Keyed by utils.GetServiceCacheKey,
+// the same key HostReactor itself uses.
+type fakeNamingProxy struct {
+	mu       sync.Mutex
+	services map[string]model.Service
+	err      error
+	calls    int
+}
+
+func (p *fakeNamingProxy) QueryList(serviceName, clusters string, udpPort int, healthyOnly bool) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.err != nil {
+		return "", p.err
+	}
+	service, ok := p.services[utils.GetServiceCacheKey(serviceName, clusters)]
+	if !ok {
+		return "", nil
+	}
+	return utils.ToJsonString(&service), nil
+}
+
+func (p *fakeNamingProxy) GetAllServiceInfoList(nameSpace, groupName string, pageNo, pageSize uint32) (string, error) {
+	return "", nil
+}
+
+func (p *fakeNamingProxy) callCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+// fakeSubscribeCallback records ServiceChanged calls so a test can assert the
+// real ProcessServiceJson code path actually fired it.
+type fakeSubscribeCallback struct {
+	mu      sync.Mutex
+	changed []string
+}
+
+func (f *fakeSubscribeCallback) ServiceChanged(service *model.Service) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.changed = append(f.changed, service.Name)
+}
+
+// newIntegrationTestHostReactor wires up the same collaborators NewHostReactor
+// does, minus the real PushReceiver (which opens a UDP socket and has nothing
+// to do with the behavior under test here). Unlike newTestHostReactor's bare
+// struct literal, these tests drive GetServiceInfo/Subscribe/ProcessServiceJson
+// through the real production code to prove the pieces actually compose.
+func newIntegrationTestHostReactor(proxy NamingProxy) *HostReactor {
+	return &HostReactor{
+		serviceProxy:    proxy,
+		updateThreadNum: Default_Update_Thread_Num,
+		serviceInfoMap:  cache.NewConcurrentMap(),
+		updateTimeMap:   cache.NewConcurrentMap(),
+		updateGroup:     newSingleflightGroup(),
+		subscribedMap:   cache.NewConcurrentMap(),
+		scheduler:       newRefreshScheduler(),
+		failureCountMap: cache.NewConcurrentMap(),
+		cacheStore:      &fakeCacheStore{services: map[string]model.Service{}},
+		subCallback:     &fakeSubscribeCallback{},
+		logger:          stdLogger{},
+	}
+}
+
+func TestGetServiceInfoFetchesLiveOnMissThenServesFromCache(t *testing.T) {
+	proxy := &fakeNamingProxy{services: map[string]model.Service{
+		utils.GetServiceCacheKey("svc", ""): {Name: "svc", Hosts: instances("a")},
+	}}
+	hr := newIntegrationTestHostReactor(proxy)
+
+	info, err := hr.GetServiceInfo(context.Background(), "svc", "")
+	if err != nil {
+		t.Fatalf("GetServiceInfo: %v", err)
+	}
+	if info.Name != "svc" || len(info.Hosts) != 1 {
+		t.Fatalf("got %+v, want the service fetched from the proxy", info)
+	}
+	if info.Stale {
+		t.Fatalf("Stale = true for a freshly fetched service, want false")
+	}
+
+	if _, err := hr.GetServiceInfo(context.Background(), "svc", ""); err != nil {
+		t.Fatalf("GetServiceInfo (second call): %v", err)
+	}
+	if got := proxy.callCount(); got != 1 {
+		t.Fatalf("got %d QueryList calls across two GetServiceInfo calls for the same key, want 1 - the second should be served from serviceInfoMap", got)
+	}
+}
+
+func TestGetServiceInfoFailsOverToDiskCacheAndReportsStale(t *testing.T) {
+	proxy := &fakeNamingProxy{err: errors.New("nacos unreachable")}
+	hr := newIntegrationTestHostReactor(proxy)
+	hr.cacheStore = &fakeCacheStore{services: map[string]model.Service{
+		utils.GetServiceCacheKey("svc", ""): {Name: "svc", Hosts: instances("a")},
+	}}
+
+	info, err := hr.GetServiceInfo(context.Background(), "svc", "")
+	if err != nil {
+		t.Fatalf("GetServiceInfo: %v", err)
+	}
+	if info.Name != "svc" {
+		t.Fatalf("got %+v, want the on-disk fallback adopted after the live fetch failed", info)
+	}
+	if !info.Stale {
+		t.Fatalf("Stale = false after a failed live fetch, want true")
+	}
+}
+
+func TestSubscribeDrivesBackgroundRefreshThroughTheScheduler(t *testing.T) {
+	key := utils.GetServiceCacheKey("svc", "")
+	proxy := &fakeNamingProxy{services: map[string]model.Service{
+		key: {Name: "svc", Hosts: instances("a"), CacheMillis: uint64(time.Hour / time.Millisecond)},
+	}}
+	hr := newIntegrationTestHostReactor(proxy)
+	go hr.asyncUpdateService()
+
+	hr.Subscribe("svc", "")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := hr.serviceInfoMap.Get(key); ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Subscribe did not cause the background loop to fetch %q within the deadline", key)
+}
+
+func TestUnsubscribeStopsFurtherBackgroundRefreshes(t *testing.T) {
+	key := utils.GetServiceCacheKey("svc", "")
+	proxy := &fakeNamingProxy{services: map[string]model.Service{
+		key: {Name: "svc", Hosts: instances("a"), CacheMillis: uint64(minRefreshIntervalMillis)},
+	}}
+	hr := newIntegrationTestHostReactor(proxy)
+	go hr.asyncUpdateService()
+
+	hr.Subscribe("svc", "")
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if proxy.callCount() > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if proxy.callCount() == 0 {
+		t.Fatalf("Subscribe never triggered an initial fetch, nothing to unsubscribe from")
+	}
+
+	hr.Unsubscribe("svc", "")
+	afterUnsubscribe := proxy.callCount()
+	// wait past the refresh interval, so a refresh that Unsubscribe failed to
+	// stop would have come due
+	time.Sleep(time.Duration(minRefreshIntervalMillis)*time.Millisecond + 500*time.Millisecond)
+	if got := proxy.callCount(); got != afterUnsubscribe {
+		t.Fatalf("got %d QueryList calls after Unsubscribe, want %d - it should stop being refreshed", got, afterUnsubscribe)
+	}
+}
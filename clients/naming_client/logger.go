@@ -0,0 +1,73 @@
+package naming_client
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger lets an embedding application route HostReactor's diagnostics into
+// its own structured logging pipeline (zap, zerolog, logrus, ...) instead of
+// the standard library's global logger. Each method takes a message plus an
+// even number of key/value pairs describing it, mirroring the go-kit/zap style
+// of structured field logging.
+type Logger interface {
+	Debug(msg string, kvs ...interface{})
+	Info(msg string, kvs ...interface{})
+	Warn(msg string, kvs ...interface{})
+	Error(msg string, kvs ...interface{})
+}
+
+// WithLogger overrides HostReactor's default stdlib-backed Logger.
+func WithLogger(logger Logger) HostReactorOption {
+	return func(hr *HostReactor) {
+		hr.logger = logger
+	}
+}
+
+// logLevel orders Logger's methods so stdLogger can filter by a minimum
+// level - Debug < Info < Warn < Error. levelInfo is the zero value, so a
+// bare stdLogger{} (as opposed to newStdLogger()) still defaults to
+// suppressing Debug rather than silently logging everything.
+type logLevel int
+
+const (
+	levelDebug logLevel = iota - 1
+	levelInfo
+	levelWarn
+	levelError
+)
+
+// stdLogger is the default Logger, backed by the standard library's global
+// logger - the same destination HostReactor always wrote to before Logger
+// existed. It suppresses Debug by default so the per-update service JSON
+// dump (now logged at Debug, see ProcessServiceJson) doesn't balloon
+// production logs for anyone who hasn't injected their own Logger.
+type stdLogger struct {
+	minLevel logLevel
+}
+
+// newStdLogger returns the default stdLogger, which logs Info and above.
+func newStdLogger() stdLogger {
+	return stdLogger{minLevel: levelInfo}
+}
+
+func (l stdLogger) Debug(msg string, kvs ...interface{}) { l.log(levelDebug, "DEBUG", msg, kvs...) }
+func (l stdLogger) Info(msg string, kvs ...interface{})  { l.log(levelInfo, "INFO", msg, kvs...) }
+func (l stdLogger) Warn(msg string, kvs ...interface{})  { l.log(levelWarn, "WARN", msg, kvs...) }
+func (l stdLogger) Error(msg string, kvs ...interface{}) { l.log(levelError, "ERROR", msg, kvs...) }
+
+func (l stdLogger) log(level logLevel, tag, msg string, kvs ...interface{}) {
+	if level < l.minLevel {
+		return
+	}
+	if len(kvs) == 0 {
+		log.Printf("[%s] %s\n", tag, msg)
+		return
+	}
+	fields := make([]string, 0, len(kvs)/2)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		fields = append(fields, fmt.Sprintf("%v:%v", kvs[i], kvs[i+1]))
+	}
+	log.Printf("[%s] %s %s\n", tag, msg, strings.Join(fields, " "))
+}
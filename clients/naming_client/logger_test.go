@@ -0,0 +1,80 @@
+package naming_client
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func captureStdLog(f func()) string {
+	var buf bytes.Buffer
+	orig := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(orig)
+	f()
+	return buf.String()
+}
+
+func TestStdLoggerFormatsKeyValuePairs(t *testing.T) {
+	out := captureStdLog(func() {
+		stdLogger{}.Info("service updated", "serviceName", "svc", "count", 3)
+	})
+
+	if !strings.Contains(out, "[INFO]") || !strings.Contains(out, "service updated") {
+		t.Fatalf("got %q, want it to contain level and message", out)
+	}
+	if !strings.Contains(out, "serviceName:svc") || !strings.Contains(out, "count:3") {
+		t.Fatalf("got %q, want formatted key:value fields", out)
+	}
+}
+
+func TestStdLoggerDropsTrailingUnpairedKV(t *testing.T) {
+	out := captureStdLog(func() {
+		stdLogger{}.Warn("odd fields", "onlyKey")
+	})
+
+	if strings.Contains(out, "onlyKey") {
+		t.Fatalf("got %q, want a trailing key with no value silently dropped rather than misformatted", out)
+	}
+}
+
+func TestStdLoggerNoFieldsOmitsTrailingSpace(t *testing.T) {
+	out := captureStdLog(func() {
+		stdLogger{}.Error("bare message")
+	})
+
+	if !strings.HasSuffix(out, "[ERROR] bare message\n") {
+		t.Fatalf("got %q, want \"[ERROR] bare message\"", out)
+	}
+}
+
+func TestStdLoggerDefaultSuppressesDebug(t *testing.T) {
+	out := captureStdLog(func() {
+		newStdLogger().Debug("service was updated", "key", "svc")
+	})
+
+	if out != "" {
+		t.Fatalf("got %q, want Debug suppressed by default so churny services don't balloon production logs", out)
+	}
+}
+
+func TestStdLoggerZeroValueAlsoSuppressesDebug(t *testing.T) {
+	out := captureStdLog(func() {
+		stdLogger{}.Debug("service was updated", "key", "svc")
+	})
+
+	if out != "" {
+		t.Fatalf("got %q, want a bare stdLogger{} to suppress Debug too, not just newStdLogger()", out)
+	}
+}
+
+func TestStdLoggerDefaultStillLogsInfoAndAbove(t *testing.T) {
+	out := captureStdLog(func() {
+		newStdLogger().Info("service not found in cache", "key", "svc")
+	})
+
+	if !strings.Contains(out, "[INFO]") {
+		t.Fatalf("got %q, want Info still logged by default", out)
+	}
+}
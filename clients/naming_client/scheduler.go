@@ -0,0 +1,106 @@
+package naming_client
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// refreshTask is one entry in a refreshScheduler's queue: key is due for a
+// refresh at dueAt (millis, see utils.CurrentMillis).
+type refreshTask struct {
+	key   string
+	dueAt uint64
+	index int
+}
+
+type refreshQueue []*refreshTask
+
+func (q refreshQueue) Len() int           { return len(q) }
+func (q refreshQueue) Less(i, j int) bool { return q[i].dueAt < q[j].dueAt }
+func (q refreshQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *refreshQueue) Push(x interface{}) {
+	task := x.(*refreshTask)
+	task.index = len(*q)
+	*q = append(*q, task)
+}
+
+func (q *refreshQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	task := old[n-1]
+	old[n-1] = nil
+	task.index = -1
+	*q = old[:n-1]
+	return task
+}
+
+// refreshScheduler tracks, for every subscribed service, the next time it is
+// due for a refresh (lastRefTime + CacheMillis) in a min-heap, so the update
+// loop can sleep exactly until the next due entry instead of polling on a
+// fixed interval. schedule/cancel wake the loop so it can react immediately
+// to a subscribe/unsubscribe call or a push-driven refresh.
+type refreshScheduler struct {
+	mu    sync.Mutex
+	queue refreshQueue
+	tasks map[string]*refreshTask
+	wake  chan struct{}
+}
+
+func newRefreshScheduler() *refreshScheduler {
+	return &refreshScheduler{
+		tasks: make(map[string]*refreshTask),
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+// schedule (re)schedules key for refresh at dueAt, replacing any pending
+// schedule for the same key.
+func (s *refreshScheduler) schedule(key string, dueAt uint64) {
+	s.mu.Lock()
+	if task, ok := s.tasks[key]; ok {
+		task.dueAt = dueAt
+		heap.Fix(&s.queue, task.index)
+	} else {
+		task := &refreshTask{key: key, dueAt: dueAt}
+		heap.Push(&s.queue, task)
+		s.tasks[key] = task
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// cancel removes key from the schedule, e.g. once nothing is subscribed to it
+// any more.
+func (s *refreshScheduler) cancel(key string) {
+	s.mu.Lock()
+	if task, ok := s.tasks[key]; ok {
+		heap.Remove(&s.queue, task.index)
+		delete(s.tasks, key)
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// next returns the key due soonest and its due time, or ok=false if nothing
+// is scheduled.
+func (s *refreshScheduler) next() (key string, dueAt uint64, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return "", 0, false
+	}
+	task := s.queue[0]
+	return task.key, task.dueAt, true
+}
+
+func (s *refreshScheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,119 @@
+package naming_client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshSchedulerNextReturnsEarliestDue(t *testing.T) {
+	s := newRefreshScheduler()
+	s.schedule("b", 200)
+	s.schedule("a", 100)
+	s.schedule("c", 300)
+
+	key, dueAt, ok := s.next()
+	if !ok {
+		t.Fatalf("next: ok=false, want a due entry")
+	}
+	if key != "a" || dueAt != 100 {
+		t.Fatalf("got (%q, %d), want (\"a\", 100)", key, dueAt)
+	}
+}
+
+func TestRefreshSchedulerEmptyHasNoNext(t *testing.T) {
+	s := newRefreshScheduler()
+	if _, _, ok := s.next(); ok {
+		t.Fatalf("next on empty scheduler: ok=true, want false")
+	}
+}
+
+func TestRefreshSchedulerRescheduleDedupsByKey(t *testing.T) {
+	s := newRefreshScheduler()
+	s.schedule("a", 500)
+	s.schedule("a", 100)
+
+	if len(s.tasks) != 1 {
+		t.Fatalf("got %d tracked tasks for one key, want 1", len(s.tasks))
+	}
+	key, dueAt, ok := s.next()
+	if !ok || key != "a" || dueAt != 100 {
+		t.Fatalf("got (%q, %d, %v), want (\"a\", 100, true) - rescheduling should update dueAt in place", key, dueAt, ok)
+	}
+}
+
+func TestRefreshSchedulerCancelRemovesKey(t *testing.T) {
+	s := newRefreshScheduler()
+	s.schedule("a", 100)
+	s.schedule("b", 200)
+	s.cancel("a")
+
+	key, dueAt, ok := s.next()
+	if !ok || key != "b" || dueAt != 200 {
+		t.Fatalf("got (%q, %d, %v), want (\"b\", 200, true) after cancelling \"a\"", key, dueAt, ok)
+	}
+	if _, _, ok := s.next(); !ok {
+		t.Fatalf("next: ok=false unexpectedly")
+	}
+
+	s.cancel("b")
+	if _, _, ok := s.next(); ok {
+		t.Fatalf("next after cancelling everything: ok=true, want false")
+	}
+}
+
+func TestRefreshSchedulerCancelUnknownKeyIsNoop(t *testing.T) {
+	s := newRefreshScheduler()
+	s.schedule("a", 100)
+	s.cancel("does-not-exist")
+
+	key, _, ok := s.next()
+	if !ok || key != "a" {
+		t.Fatalf("cancelling an unknown key disturbed the schedule: got (%q, %v)", key, ok)
+	}
+}
+
+func TestRefreshSchedulerPopOrderMatchesDueOrder(t *testing.T) {
+	s := newRefreshScheduler()
+	due := map[string]uint64{"c": 300, "a": 100, "e": 500, "b": 200, "d": 400}
+	for key, dueAt := range due {
+		s.schedule(key, dueAt)
+	}
+
+	var order []string
+	for {
+		key, _, ok := s.next()
+		if !ok {
+			break
+		}
+		order = append(order, key)
+		s.cancel(key)
+	}
+
+	want := []string{"a", "b", "c", "d", "e"}
+	if len(order) != len(want) {
+		t.Fatalf("got order %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("got order %v, want %v", order, want)
+		}
+	}
+}
+
+func TestRefreshSchedulerScheduleWakesUpWaiter(t *testing.T) {
+	s := newRefreshScheduler()
+	// drain the buffered slot newRefreshScheduler doesn't pre-fill, so this
+	// test only sees the wake caused by the schedule call below.
+	select {
+	case <-s.wake:
+	default:
+	}
+
+	s.schedule("a", 100)
+
+	select {
+	case <-s.wake:
+	case <-time.After(time.Second):
+		t.Fatalf("schedule did not signal wake")
+	}
+}
@@ -0,0 +1,238 @@
+package naming_client
+
+import (
+	"errors"
+	"hash/crc32"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/nacos-group/nacos-sdk-go/model"
+)
+
+// SelectorType identifies one of the load-balancing strategies a caller can ask
+// HostReactor to apply when picking an instance out of a service's host list.
+type SelectorType int
+
+const (
+	SelectorWeightedRandom SelectorType = iota
+	SelectorRoundRobin
+	SelectorConsistentHash
+)
+
+const consistentHashVirtualNodePerInstance = 160
+
+// Selector picks one instance out of the host list it was built from. Select
+// is called with a routing key; strategies that don't use the key (weighted
+// random, round-robin) simply ignore it.
+type Selector interface {
+	Select(key string) (model.Instance, error)
+}
+
+// MultiSelector is implemented by selectors for which repeatedly calling
+// Select with the same key can't be used to pick several distinct instances -
+// consistentHashSelector, whose Select is deterministic per key, and
+// weightedRandomSelector, whose repeated draws can keep hitting the same
+// instances. SelectInstances uses it instead of the naive retry loop when present.
+type MultiSelector interface {
+	SelectN(key string, count int) ([]model.Instance, error)
+}
+
+func newSelector(selectorType SelectorType, instances []model.Instance) Selector {
+	switch selectorType {
+	case SelectorRoundRobin:
+		return newRoundRobinSelector(instances)
+	case SelectorConsistentHash:
+		return newConsistentHashSelector(instances)
+	default:
+		return newWeightedRandomSelector(instances)
+	}
+}
+
+var errNoAvailableInstance = errors.New("no available instance")
+
+// errUnknownSelectorType guards cachedService.selector against a SelectorType
+// outside the three constants newCachedService precomputes for - SelectorType
+// is an unconstrained int, so a caller passing e.g. SelectorType(99) would
+// otherwise get a nil Selector back.
+var errUnknownSelectorType = errors.New("unknown selector type")
+
+// weightedRandomSelector picks an instance at random, weighted by Instance.Weight,
+// via a cumulative-weight table rebuilt whenever the host list changes.
+type weightedRandomSelector struct {
+	instances []model.Instance
+	cumWeight []float64
+	total     float64
+}
+
+func newWeightedRandomSelector(instances []model.Instance) *weightedRandomSelector {
+	s := &weightedRandomSelector{instances: instances, cumWeight: make([]float64, len(instances))}
+	total := 0.0
+	for i, instance := range instances {
+		weight := instance.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		total += weight
+		s.cumWeight[i] = total
+	}
+	s.total = total
+	return s
+}
+
+func (s *weightedRandomSelector) Select(key string) (model.Instance, error) {
+	if len(s.instances) == 0 || s.total <= 0 {
+		return model.Instance{}, errNoAvailableInstance
+	}
+	target := rand.Float64() * s.total
+	idx := sort.SearchFloat64s(s.cumWeight, target)
+	if idx >= len(s.instances) {
+		idx = len(s.instances) - 1
+	}
+	return s.instances[idx], nil
+}
+
+// SelectN returns up to count distinct instances, weighted-sampled without
+// replacement: each pick narrows the pool to what's left and re-draws from
+// its weights. Unlike calling Select repeatedly until enough distinct
+// instances turn up (which can exhaust its attempt budget on weight
+// collisions without covering every instance), it can't under-fill when
+// count <= the number of instances.
+func (s *weightedRandomSelector) SelectN(key string, count int) ([]model.Instance, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	if len(s.instances) == 0 || s.total <= 0 {
+		return nil, errNoAvailableInstance
+	}
+	if count > len(s.instances) {
+		count = len(s.instances)
+	}
+	remaining := append([]model.Instance(nil), s.instances...)
+	weights := make([]float64, len(remaining))
+	total := 0.0
+	for i, instance := range remaining {
+		weight := instance.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		weights[i] = weight
+		total += weight
+	}
+
+	result := make([]model.Instance, 0, count)
+	for len(result) < count {
+		target := rand.Float64() * total
+		cum, idx := 0.0, len(remaining)-1
+		for i, weight := range weights {
+			cum += weight
+			if target < cum {
+				idx = i
+				break
+			}
+		}
+		result = append(result, remaining[idx])
+		total -= weights[idx]
+		remaining = append(remaining[:idx], remaining[idx+1:]...)
+		weights = append(weights[:idx], weights[idx+1:]...)
+	}
+	return result, nil
+}
+
+// roundRobinSelector cycles through instances in order using an atomic counter
+// kept for the lifetime of the selector, i.e. per cache key.
+type roundRobinSelector struct {
+	instances []model.Instance
+	counter   uint64
+}
+
+func newRoundRobinSelector(instances []model.Instance) *roundRobinSelector {
+	return &roundRobinSelector{instances: instances}
+}
+
+func (s *roundRobinSelector) Select(key string) (model.Instance, error) {
+	if len(s.instances) == 0 {
+		return model.Instance{}, errNoAvailableInstance
+	}
+	n := atomic.AddUint64(&s.counter, 1)
+	return s.instances[int(n-1)%len(s.instances)], nil
+}
+
+// ringNode is one virtual node on a consistentHashSelector's ring. Carrying
+// the instance alongside its point - rather than looking it up from a
+// map[uint32]model.Instance keyed solely by point - means a hash collision
+// between two virtual nodes (possible past roughly a hundred instances at
+// consistentHashVirtualNodePerInstance points each, by the birthday paradox)
+// leaves both nodes' instances in the ring instead of one silently
+// overwriting the other's map entry.
+type ringNode struct {
+	point    uint32
+	instance model.Instance
+}
+
+// consistentHashSelector implements Ketama-style consistent hashing: each
+// instance is hashed onto ~160 points on a ring so that requests for the same
+// key keep landing on the same instance across minor membership churn.
+type consistentHashSelector struct {
+	ring          []ringNode
+	instanceCount int
+}
+
+func newConsistentHashSelector(instances []model.Instance) *consistentHashSelector {
+	s := &consistentHashSelector{instanceCount: len(instances)}
+	for _, instance := range instances {
+		for v := 0; v < consistentHashVirtualNodePerInstance; v++ {
+			point := hashKey(instance.InstanceId + "#" + strconv.Itoa(v))
+			s.ring = append(s.ring, ringNode{point: point, instance: instance})
+		}
+	}
+	sort.Slice(s.ring, func(i, j int) bool { return s.ring[i].point < s.ring[j].point })
+	return s
+}
+
+func (s *consistentHashSelector) Select(key string) (model.Instance, error) {
+	if len(s.ring) == 0 {
+		return model.Instance{}, errNoAvailableInstance
+	}
+	point := hashKey(key)
+	idx := sort.Search(len(s.ring), func(i int) bool { return s.ring[i].point >= point })
+	if idx == len(s.ring) {
+		idx = 0
+	}
+	return s.ring[idx].instance, nil
+}
+
+// SelectN returns up to count distinct instances for key by walking the ring
+// clockwise from key's point, the same successor order each of those
+// instances would take over for key if the ones before it failed. Select
+// alone can't do this: it's deterministic per key, so calling it count times
+// would just return the same instance count times.
+func (s *consistentHashSelector) SelectN(key string, count int) ([]model.Instance, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	if len(s.ring) == 0 {
+		return nil, errNoAvailableInstance
+	}
+	if count > s.instanceCount {
+		count = s.instanceCount
+	}
+	point := hashKey(key)
+	start := sort.Search(len(s.ring), func(i int) bool { return s.ring[i].point >= point })
+	instances := make([]model.Instance, 0, count)
+	seen := make(map[string]bool, count)
+	for i := 0; i < len(s.ring) && len(instances) < count; i++ {
+		instance := s.ring[(start+i)%len(s.ring)].instance
+		if seen[instance.InstanceId] {
+			continue
+		}
+		seen[instance.InstanceId] = true
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+func hashKey(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
@@ -0,0 +1,289 @@
+package naming_client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/model"
+)
+
+func instances(ids ...string) []model.Instance {
+	result := make([]model.Instance, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, model.Instance{InstanceId: id, Weight: 1})
+	}
+	return result
+}
+
+func distinctIds(got []model.Instance) map[string]bool {
+	seen := make(map[string]bool, len(got))
+	for _, instance := range got {
+		seen[instance.InstanceId] = true
+	}
+	return seen
+}
+
+func TestWeightedRandomSelectorDistribution(t *testing.T) {
+	hosts := []model.Instance{
+		{InstanceId: "heavy", Weight: 9},
+		{InstanceId: "light", Weight: 1},
+	}
+	s := newWeightedRandomSelector(hosts)
+
+	const trials = 20000
+	counts := map[string]int{}
+	for i := 0; i < trials; i++ {
+		instance, err := s.Select("")
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		counts[instance.InstanceId]++
+	}
+
+	ratio := float64(counts["heavy"]) / float64(trials)
+	if ratio < 0.8 || ratio > 0.95 {
+		t.Fatalf("heavy instance selected %.3f of the time, want close to 0.9 (counts=%v)", ratio, counts)
+	}
+}
+
+func TestWeightedRandomSelectorEmpty(t *testing.T) {
+	s := newWeightedRandomSelector(nil)
+	if _, err := s.Select(""); err != errNoAvailableInstance {
+		t.Fatalf("got err %v, want errNoAvailableInstance", err)
+	}
+}
+
+func TestWeightedRandomSelectorSelectNDistinct(t *testing.T) {
+	hosts := instances("a", "b", "c", "d", "e")
+	s := newWeightedRandomSelector(hosts)
+
+	for count := 1; count <= len(hosts); count++ {
+		t.Run(fmt.Sprintf("count=%d", count), func(t *testing.T) {
+			got, err := s.SelectN("key", count)
+			if err != nil {
+				t.Fatalf("SelectN: %v", err)
+			}
+			if len(got) != count {
+				t.Fatalf("got %d instances, want %d", len(got), count)
+			}
+			if seen := distinctIds(got); len(seen) != count {
+				t.Fatalf("got %d distinct instances, want %d (result=%v)", len(seen), count, got)
+			}
+		})
+	}
+}
+
+func TestWeightedRandomSelectorSelectNClampsToAvailable(t *testing.T) {
+	s := newWeightedRandomSelector(instances("a", "b"))
+	got, err := s.SelectN("key", 10)
+	if err != nil {
+		t.Fatalf("SelectN: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d instances, want 2 (clamped to available)", len(got))
+	}
+}
+
+func TestWeightedRandomSelectorSelectNNonPositiveCountReturnsNil(t *testing.T) {
+	s := newWeightedRandomSelector(instances("a", "b"))
+
+	got, err := s.SelectN("key", -1)
+	if err != nil {
+		t.Fatalf("SelectN(-1): %v", err)
+	}
+	if got != nil {
+		t.Fatalf("SelectN(-1) = %v, want nil", got)
+	}
+}
+
+func TestRoundRobinSelectorCyclesInOrder(t *testing.T) {
+	hosts := instances("a", "b", "c")
+	s := newRoundRobinSelector(hosts)
+
+	var got []string
+	for i := 0; i < 6; i++ {
+		instance, err := s.Select("")
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		got = append(got, instance.InstanceId)
+	}
+	want := []string{"a", "b", "c", "a", "b", "c"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("call %d: got %q, want %q (full sequence %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestRoundRobinSelectorDistinctWithinOneCycle(t *testing.T) {
+	hosts := instances("a", "b", "c", "d")
+	s := newRoundRobinSelector(hosts)
+
+	got := make([]model.Instance, 0, len(hosts))
+	for i := 0; i < len(hosts); i++ {
+		instance, err := s.Select("")
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		got = append(got, instance)
+	}
+	if seen := distinctIds(got); len(seen) != len(hosts) {
+		t.Fatalf("got %d distinct instances in one cycle, want %d", len(seen), len(hosts))
+	}
+}
+
+func TestRoundRobinSelectorEmpty(t *testing.T) {
+	s := newRoundRobinSelector(nil)
+	if _, err := s.Select(""); err != errNoAvailableInstance {
+		t.Fatalf("got err %v, want errNoAvailableInstance", err)
+	}
+}
+
+func TestConsistentHashSelectorStableForSameKey(t *testing.T) {
+	hosts := instances("a", "b", "c", "d", "e")
+	s := newConsistentHashSelector(hosts)
+
+	first, err := s.Select("routing-key")
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		got, err := s.Select("routing-key")
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		if got.InstanceId != first.InstanceId {
+			t.Fatalf("call %d: got %q, want stable result %q", i, got.InstanceId, first.InstanceId)
+		}
+	}
+}
+
+func TestConsistentHashSelectorStableAcrossMembershipChurn(t *testing.T) {
+	all := []string{"a", "b", "c", "d", "e"}
+	before := newConsistentHashSelector(instances(all...))
+	key := "some-routing-key"
+	want, err := before.Select(key)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+
+	// removing an instance other than the one key hashed to should not move
+	// key's assignment - that's the whole point of consistent hashing over a
+	// plain mod-N hash.
+	var removeOther string
+	for _, id := range all {
+		if id != want.InstanceId {
+			removeOther = id
+			break
+		}
+	}
+	remaining := make([]string, 0, len(all)-1)
+	for _, id := range all {
+		if id != removeOther {
+			remaining = append(remaining, id)
+		}
+	}
+
+	after := newConsistentHashSelector(instances(remaining...))
+	got, err := after.Select(key)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if got.InstanceId != want.InstanceId {
+		t.Fatalf("removing an unrelated instance moved key's assignment: got %q, want %q", got.InstanceId, want.InstanceId)
+	}
+}
+
+func TestConsistentHashSelectorEmpty(t *testing.T) {
+	s := newConsistentHashSelector(nil)
+	if _, err := s.Select("key"); err != errNoAvailableInstance {
+		t.Fatalf("got err %v, want errNoAvailableInstance", err)
+	}
+}
+
+func TestConsistentHashSelectorSelectNDistinctSuccessors(t *testing.T) {
+	hosts := instances("a", "b", "c", "d", "e")
+	s := newConsistentHashSelector(hosts)
+
+	for count := 1; count <= len(hosts); count++ {
+		t.Run(fmt.Sprintf("count=%d", count), func(t *testing.T) {
+			got, err := s.SelectN("key", count)
+			if err != nil {
+				t.Fatalf("SelectN: %v", err)
+			}
+			if len(got) != count {
+				t.Fatalf("got %d instances, want %d", len(got), count)
+			}
+			if seen := distinctIds(got); len(seen) != count {
+				t.Fatalf("got %d distinct instances, want %d (result=%v)", len(seen), count, got)
+			}
+		})
+	}
+}
+
+func TestConsistentHashSelectorSelectNStartsWithSingleSelectResult(t *testing.T) {
+	hosts := instances("a", "b", "c", "d", "e")
+	s := newConsistentHashSelector(hosts)
+
+	single, err := s.Select("key")
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	many, err := s.SelectN("key", 3)
+	if err != nil {
+		t.Fatalf("SelectN: %v", err)
+	}
+	if len(many) == 0 || many[0].InstanceId != single.InstanceId {
+		t.Fatalf("SelectN's first pick %v does not match Select's result %v", many, single)
+	}
+}
+
+func TestConsistentHashSelectorSurvivesPointCollision(t *testing.T) {
+	// Regression test for the ring keying its virtual nodes by point alone in
+	// a map[uint32]model.Instance: two virtual nodes landing on the same
+	// crc32 point (possible past ~100 instances by the birthday paradox)
+	// would silently overwrite one another, permanently losing one
+	// instance's coverage for that point. Each ringNode now carries its own
+	// instance, so both survive even when their points collide.
+	a := model.Instance{InstanceId: "a"}
+	b := model.Instance{InstanceId: "b"}
+	s := &consistentHashSelector{
+		ring: []ringNode{
+			{point: 100, instance: a},
+			{point: 100, instance: b},
+		},
+		instanceCount: 2,
+	}
+
+	got, err := s.SelectN("key", 2)
+	if err != nil {
+		t.Fatalf("SelectN: %v", err)
+	}
+	if seen := distinctIds(got); len(seen) != 2 {
+		t.Fatalf("got %d distinct instances out of a 2-way point collision, want 2 (result=%v)", len(seen), got)
+	}
+}
+
+func TestConsistentHashSelectorSelectNNonPositiveCountReturnsNil(t *testing.T) {
+	s := newConsistentHashSelector(instances("a", "b"))
+
+	got, err := s.SelectN("key", -1)
+	if err != nil {
+		t.Fatalf("SelectN(-1): %v", err)
+	}
+	if got != nil {
+		t.Fatalf("SelectN(-1) = %v, want nil", got)
+	}
+}
+
+func TestCachedServiceSelectorUnknownTypeReturnsNil(t *testing.T) {
+	cs := newCachedService(model.Service{Hosts: instances("a", "b")})
+
+	if got := cs.selector(SelectorType(99)); got != nil {
+		t.Fatalf("selector(unknown type) = %v, want nil so callers can return errUnknownSelectorType instead of panicking", got)
+	}
+	if got := cs.selector(SelectorWeightedRandom); got == nil {
+		t.Fatalf("selector(SelectorWeightedRandom) = nil, want a precomputed Selector")
+	}
+}
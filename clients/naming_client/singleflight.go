@@ -0,0 +1,61 @@
+package naming_client
+
+import "sync"
+
+// singleflightResult is what a singleflightGroup call delivers on its
+// result channel once the in-flight fn has finished.
+type singleflightResult struct {
+	val interface{}
+	err error
+}
+
+type singleflightCall struct {
+	wg  sync.WaitGroup
+	val interface{}
+	err error
+}
+
+// singleflightGroup deduplicates concurrent work sharing the same key, so that
+// e.g. two goroutines asking for the same service only trigger one HTTP call
+// while goroutines asking for different services still run fully in parallel.
+type singleflightGroup struct {
+	mu sync.Mutex
+	m  map[string]*singleflightCall
+}
+
+func newSingleflightGroup() *singleflightGroup {
+	return &singleflightGroup{m: make(map[string]*singleflightCall)}
+}
+
+// DoChan runs fn for key, or if a call for key is already in flight, waits on
+// that call instead of starting a new one. The result is delivered on the
+// returned channel so the caller can select on it alongside e.g. ctx.Done().
+func (g *singleflightGroup) DoChan(key string, fn func() (interface{}, error)) <-chan singleflightResult {
+	ch := make(chan singleflightResult, 1)
+
+	g.mu.Lock()
+	if c, ok := g.m[key]; ok {
+		g.mu.Unlock()
+		go func() {
+			c.wg.Wait()
+			ch <- singleflightResult{c.val, c.err}
+		}()
+		return ch
+	}
+	c := new(singleflightCall)
+	c.wg.Add(1)
+	g.m[key] = c
+	g.mu.Unlock()
+
+	go func() {
+		c.val, c.err = fn()
+		c.wg.Done()
+
+		g.mu.Lock()
+		delete(g.m, key)
+		g.mu.Unlock()
+
+		ch <- singleflightResult{c.val, c.err}
+	}()
+	return ch
+}
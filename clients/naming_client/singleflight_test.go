@@ -0,0 +1,121 @@
+package naming_client
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSingleflightGroupDedupsConcurrentCallsForSameKey(t *testing.T) {
+	g := newSingleflightGroup()
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	fn := func() (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-release
+		}
+		return "value", nil
+	}
+
+	const callers = 5
+	results := make([]<-chan singleflightResult, callers)
+	for i := 0; i < callers; i++ {
+		results[i] = g.DoChan("key", fn)
+	}
+
+	<-started
+	close(release)
+
+	for i, ch := range results {
+		select {
+		case r := <-ch:
+			if r.err != nil {
+				t.Fatalf("caller %d: unexpected error: %v", i, r.err)
+			}
+			if r.val != "value" {
+				t.Fatalf("caller %d: got %v, want %q", i, r.val, "value")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("caller %d: timed out waiting for result", i)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("fn ran %d times, want 1", got)
+	}
+}
+
+func TestSingleflightGroupRunsDifferentKeysInParallel(t *testing.T) {
+	g := newSingleflightGroup()
+	var wg sync.WaitGroup
+	release := make(chan struct{})
+
+	keys := []string{"a", "b", "c"}
+	var running int32
+	var maxRunning int32
+	results := make([]<-chan singleflightResult, len(keys))
+
+	for i, key := range keys {
+		wg.Add(1)
+		key := key
+		results[i] = g.DoChan(key, func() (interface{}, error) {
+			defer wg.Done()
+			n := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&maxRunning)
+				if n <= old || atomic.CompareAndSwapInt32(&maxRunning, old, n) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&running, -1)
+			return key, nil
+		})
+	}
+
+	// give every goroutine a chance to start before releasing them together
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i, ch := range results {
+		r := <-ch
+		if r.val != keys[i] {
+			t.Fatalf("result %d: got %v, want %q", i, r.val, keys[i])
+		}
+	}
+	if atomic.LoadInt32(&maxRunning) < 2 {
+		t.Fatalf("distinct keys never ran concurrently, maxRunning=%d", maxRunning)
+	}
+}
+
+func TestSingleflightGroupPropagatesError(t *testing.T) {
+	g := newSingleflightGroup()
+	wantErr := errors.New("boom")
+	ch := g.DoChan("key", func() (interface{}, error) {
+		return nil, wantErr
+	})
+	r := <-ch
+	if r.err != wantErr {
+		t.Fatalf("got err %v, want %v", r.err, wantErr)
+	}
+}
+
+func TestSingleflightGroupForgetsKeyAfterCompletion(t *testing.T) {
+	g := newSingleflightGroup()
+	var calls int32
+	fn := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, nil
+	}
+	<-g.DoChan("key", fn)
+	<-g.DoChan("key", fn)
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("fn ran %d times across sequential calls, want 2", got)
+	}
+}